@@ -0,0 +1,61 @@
+// Package blockupgrade implements a translation pipeline that rewrites block states between protocol versions,
+// applying the same kind of block, property and value renames Mojang ships as block state upgrade schemas.
+package blockupgrade
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/Flonja/tedac/latestmappings"
+)
+
+var (
+	mu       sync.RWMutex
+	schemas  = map[int32]Schema{}
+	versions []int32
+)
+
+// RegisterSchema registers a block-state upgrade schema that applies when translating between the version
+// before it and version. Schemas are chained in ascending version order by Upgrade and Downgrade.
+func RegisterSchema(version int32, s Schema) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if _, ok := schemas[version]; !ok {
+		versions = append(versions, version)
+		sort.Slice(versions, func(i, j int) bool { return versions[i] < versions[j] })
+	}
+	schemas[version] = s
+}
+
+// Upgrade applies every schema registered for a version newer than fromVersion, in ascending order, to s. It is
+// used to translate a block state produced by an older client into the form a newer server expects.
+func Upgrade(s latestmappings.State, fromVersion int32) latestmappings.State {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	for _, v := range versions {
+		if v <= fromVersion {
+			continue
+		}
+		s = schemas[v].apply(s)
+	}
+	return s
+}
+
+// Downgrade applies every schema registered for a version newer than toVersion, in descending order, to s in
+// reverse. It is used to translate a block state produced by a newer server into the form an older client
+// expects.
+func Downgrade(s latestmappings.State, toVersion int32) latestmappings.State {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	for i := len(versions) - 1; i >= 0; i-- {
+		v := versions[i]
+		if v <= toVersion {
+			continue
+		}
+		s = schemas[v].unapply(s)
+	}
+	return s
+}
@@ -0,0 +1,172 @@
+package blockupgrade
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/Flonja/tedac/latestmappings"
+)
+
+// Schema represents a single block-state upgrade schema step, in the same format Mojang's vanilla game uses to
+// describe how block states changed between two versions. Schemas may be parsed straight from the upstream JSON
+// files using SchemaFromJSON.
+type Schema struct {
+	// RenamedIDs maps an old block name to the new name it was renamed to.
+	RenamedIDs map[string]string `json:"renamedIds,omitempty"`
+	// RenamedProperties maps a block name to a map of old property name to new property name.
+	RenamedProperties map[string]map[string]string `json:"renamedProperties,omitempty"`
+	// RemappedPropertyValues maps a block name to a property name to a map of old value to new value. Old values
+	// are keyed, and compared, by their string representation, since properties may be encoded as either strings
+	// or numbers in the upstream schemas. New values are stored as typed values, with JSON numbers converted to
+	// int32 by SchemaFromJSON.
+	RemappedPropertyValues map[string]map[string]map[string]any `json:"remappedPropertyValues,omitempty"`
+	// AddedProperties maps a block name to a map of property name to the default value it should take on if the
+	// property did not exist yet.
+	AddedProperties map[string]map[string]any `json:"addedProperties,omitempty"`
+	// RemovedProperties maps a block name to the property names that were removed from it.
+	RemovedProperties map[string][]string `json:"removedProperties,omitempty"`
+}
+
+// SchemaFromJSON parses a Schema from b, which must be JSON matching the format used by Mojang's vanilla block
+// state upgrade schemas.
+func SchemaFromJSON(b []byte) (Schema, error) {
+	var s Schema
+	if err := json.Unmarshal(b, &s); err != nil {
+		return Schema{}, fmt.Errorf("blockupgrade: decode schema: %w", err)
+	}
+	for _, properties := range s.AddedProperties {
+		normalizeJSONValues(properties)
+	}
+	for _, values := range s.RemappedPropertyValues {
+		for _, remapped := range values {
+			normalizeJSONValues(remapped)
+		}
+	}
+	return s, nil
+}
+
+// normalizeJSONValue converts a value decoded by encoding/json into the exact Go type latestmappings stores
+// block properties as: JSON numbers decode to float64, but state properties are encoded as int32, so they are
+// converted here. Strings and booleans already decode to the right type and pass through unchanged.
+func normalizeJSONValue(v any) any {
+	if f, ok := v.(float64); ok {
+		return int32(f)
+	}
+	return v
+}
+
+// normalizeJSONValues applies normalizeJSONValue to every value in properties, in place.
+func normalizeJSONValues(properties map[string]any) {
+	for k, v := range properties {
+		properties[k] = normalizeJSONValue(v)
+	}
+}
+
+// parseSchemaValue parses s, the string representation a RemappedPropertyValues old value is keyed by, back
+// into the typed value latestmappings expects it to be, mirroring normalizeJSONValue.
+func parseSchemaValue(s string) any {
+	if i, err := strconv.ParseInt(s, 10, 32); err == nil {
+		return int32(i)
+	}
+	if b, err := strconv.ParseBool(s); err == nil {
+		return b
+	}
+	return s
+}
+
+// apply rewrites state according to the schema, in the direction old -> new: renaming the block and its
+// properties, remapping property values, and adding/removing properties.
+func (s Schema) apply(state latestmappings.State) latestmappings.State {
+	if newName, ok := s.RenamedIDs[state.Name]; ok {
+		state.Name = newName
+	}
+	state.Properties = cloneProperties(state.Properties)
+
+	if renamed, ok := s.RenamedProperties[state.Name]; ok {
+		for oldProp, newProp := range renamed {
+			if v, ok := state.Properties[oldProp]; ok {
+				delete(state.Properties, oldProp)
+				state.Properties[newProp] = v
+			}
+		}
+	}
+	if remapped, ok := s.RemappedPropertyValues[state.Name]; ok {
+		for prop, values := range remapped {
+			if v, ok := state.Properties[prop]; ok {
+				if newValue, ok := values[fmt.Sprint(v)]; ok {
+					state.Properties[prop] = newValue
+				}
+			}
+		}
+	}
+	if added, ok := s.AddedProperties[state.Name]; ok {
+		for prop, v := range added {
+			if _, ok := state.Properties[prop]; !ok {
+				state.Properties[prop] = v
+			}
+		}
+	}
+	if removed, ok := s.RemovedProperties[state.Name]; ok {
+		for _, prop := range removed {
+			delete(state.Properties, prop)
+		}
+	}
+	return state
+}
+
+// unapply rewrites state according to the schema in reverse, new -> old. Properties the schema removed cannot be
+// recovered from the schema alone and are left unset; callers translating into an older State should fill those
+// back in using latestmappings.Defaults.
+func (s Schema) unapply(state latestmappings.State) latestmappings.State {
+	state.Properties = cloneProperties(state.Properties)
+
+	if added, ok := s.AddedProperties[state.Name]; ok {
+		for prop := range added {
+			delete(state.Properties, prop)
+		}
+	}
+	if remapped, ok := s.RemappedPropertyValues[state.Name]; ok {
+		for prop, values := range remapped {
+			if v, ok := state.Properties[prop]; ok {
+				for oldValue, newValue := range values {
+					if fmt.Sprint(newValue) == fmt.Sprint(v) {
+						state.Properties[prop] = parseSchemaValue(oldValue)
+						break
+					}
+				}
+			}
+		}
+	}
+	if renamed, ok := s.RenamedProperties[state.Name]; ok {
+		for oldProp, newProp := range renamed {
+			if v, ok := state.Properties[newProp]; ok {
+				delete(state.Properties, newProp)
+				state.Properties[oldProp] = v
+			}
+		}
+	}
+	// Two legacy names may have been merged into the same new name (e.g. several stone_slab variants), so more
+	// than one oldName can match here. Prefer the lowest oldName so the result is at least deterministic; there
+	// is no way to recover which legacy id was the true origin from the schema alone.
+	oldName, matched := "", false
+	for candidate, newName := range s.RenamedIDs {
+		if newName == state.Name && (!matched || candidate < oldName) {
+			oldName, matched = candidate, true
+		}
+	}
+	if matched {
+		state.Name = oldName
+	}
+	return state
+}
+
+// cloneProperties returns a shallow copy of properties, so that Schema.apply/unapply never mutate the map of the
+// State passed in by the caller.
+func cloneProperties(properties map[string]any) map[string]any {
+	clone := make(map[string]any, len(properties))
+	for k, v := range properties {
+		clone[k] = v
+	}
+	return clone
+}
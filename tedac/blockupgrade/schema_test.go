@@ -0,0 +1,69 @@
+package blockupgrade
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Flonja/tedac/latestmappings"
+)
+
+func TestSchemaApplyUnapplyRoundTrip(t *testing.T) {
+	schema, err := SchemaFromJSON([]byte(`{
+		"renamedIds": {"minecraft:old_stone": "minecraft:new_stone"},
+		"renamedProperties": {"minecraft:new_stone": {"old_direction": "direction"}},
+		"remappedPropertyValues": {"minecraft:new_stone": {"direction": {"2": 7}}},
+		"addedProperties": {"minecraft:new_stone": {"polished": false}},
+		"removedProperties": {"minecraft:new_stone": ["legacy_data"]}
+	}`))
+	if err != nil {
+		t.Fatalf("SchemaFromJSON: %v", err)
+	}
+
+	original := latestmappings.State{
+		Name: "minecraft:old_stone",
+		Properties: map[string]any{
+			"old_direction": int32(2),
+			"legacy_data":   int32(5),
+		},
+	}
+
+	upgraded := schema.apply(original)
+	wantUpgraded := map[string]any{
+		"direction": int32(7),
+		"polished":  false,
+	}
+	if upgraded.Name != "minecraft:new_stone" || !reflect.DeepEqual(upgraded.Properties, wantUpgraded) {
+		t.Fatalf("apply() = %+v, want name minecraft:new_stone properties %+v", upgraded, wantUpgraded)
+	}
+	if _, ok := original.Properties["direction"]; ok {
+		t.Fatalf("apply() mutated the caller's properties map")
+	}
+
+	downgraded := schema.unapply(upgraded)
+	// legacy_data was in removedProperties, so it can't be recovered from the schema alone and is intentionally
+	// left unset; everything else should round trip back to the original state.
+	wantDowngraded := map[string]any{
+		"old_direction": int32(2),
+	}
+	if downgraded.Name != original.Name || !reflect.DeepEqual(downgraded.Properties, wantDowngraded) {
+		t.Fatalf("unapply() = %+v, want name %q properties %+v", downgraded, original.Name, wantDowngraded)
+	}
+}
+
+func TestSchemaUnapplyRenamedIDsTieBreak(t *testing.T) {
+	// Three legacy names all collapsed onto the same new name; unapply can't know which one was the true
+	// origin, so it must deterministically pick the lowest, not whichever the map happens to iterate first.
+	schema := Schema{
+		RenamedIDs: map[string]string{
+			"minecraft:stone_slab3": "minecraft:stone_slab",
+			"minecraft:stone_slab2": "minecraft:stone_slab",
+			"minecraft:stone_slab4": "minecraft:stone_slab",
+		},
+	}
+	state := latestmappings.State{Name: "minecraft:stone_slab"}
+	for i := 0; i < 10; i++ {
+		if got := schema.unapply(state); got.Name != "minecraft:stone_slab2" {
+			t.Fatalf("unapply() picked %q, want deterministic lowest name minecraft:stone_slab2", got.Name)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package latestmappings
+
+// Defaults returns the default state properties of the block with the given name: the properties of the first
+// state registered for that name, in the canonical order declared in the source data. The returned map is a
+// copy, so callers are free to mutate it, e.g. to pre-fill a partial state before calling StateToRuntimeIDFuzzy.
+func (m *Mappings) Defaults(name string) (properties map[string]any, found bool) {
+	defaults, ok := m.defaults[name]
+	if !ok {
+		return nil, false
+	}
+	return cloneProperties(defaults), true
+}
+
+// StateToRuntimeIDFuzzy converts a name and a partial set of its state properties to a runtime ID. It is meant
+// for translating a state produced by an older, lossier version, whose property map may be missing properties
+// that only exist in this Mappings' (newer) schema.
+//
+// Of all registered states for name, it picks the one whose properties are a superset of partial and whose
+// remaining properties equal that block's declared Defaults. If more than one state matches, the one with the
+// lowest runtime ID is preferred.
+func (m *Mappings) StateToRuntimeIDFuzzy(name string, partial map[string]any) (runtimeID uint32, found bool) {
+	defaults, ok := m.defaults[name]
+	if !ok {
+		return 0, false
+	}
+
+	for _, rid := range m.runtimeIDsByName[name] {
+		properties := m.runtimeIDToState[rid].Properties
+		if !supersetOf(properties, partial) || !remainderMatches(properties, partial, defaults) {
+			continue
+		}
+		if !found || rid < runtimeID {
+			runtimeID, found = rid, true
+		}
+	}
+	return runtimeID, found
+}
+
+// supersetOf reports whether properties contains every key/value pair in partial.
+func supersetOf(properties, partial map[string]any) bool {
+	for k, v := range partial {
+		if pv, ok := properties[k]; !ok || pv != v {
+			return false
+		}
+	}
+	return true
+}
+
+// remainderMatches reports whether every property of properties not present in partial equals the value
+// declared for it in defaults.
+func remainderMatches(properties, partial, defaults map[string]any) bool {
+	for k, v := range properties {
+		if _, ok := partial[k]; ok {
+			continue
+		}
+		if dv, ok := defaults[k]; !ok || dv != v {
+			return false
+		}
+	}
+	return true
+}
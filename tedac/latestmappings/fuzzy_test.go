@@ -0,0 +1,55 @@
+package latestmappings
+
+import "testing"
+
+// newTestMappings builds a minimal Mappings by hand, standing in for block_states.nbt, with one block
+// ("minecraft:stone") registered in three states so matching and tie-breaking can be exercised directly.
+func newTestMappings() *Mappings {
+	return &Mappings{
+		runtimeIDToState: map[uint32]State{
+			0: {Name: "minecraft:stone", Properties: map[string]any{"stone_type": "stone", "polished": false}},
+			1: {Name: "minecraft:stone", Properties: map[string]any{"stone_type": "granite", "polished": false}},
+			2: {Name: "minecraft:stone", Properties: map[string]any{"stone_type": "granite", "polished": true}},
+		},
+		runtimeIDsByName: map[string][]uint32{
+			"minecraft:stone": {0, 1, 2},
+		},
+		defaults: map[string]map[string]any{
+			"minecraft:stone": {"stone_type": "stone", "polished": false},
+		},
+	}
+}
+
+func TestMappingsDefaultsReturnsACopy(t *testing.T) {
+	m := newTestMappings()
+
+	d, ok := m.Defaults("minecraft:stone")
+	if !ok {
+		t.Fatal("Defaults(minecraft:stone) not found")
+	}
+	d["polished"] = true
+
+	again, _ := m.Defaults("minecraft:stone")
+	if again["polished"] != false {
+		t.Fatalf("mutating the returned map corrupted the Mappings' internal defaults: polished = %v", again["polished"])
+	}
+}
+
+func TestMappingsStateToRuntimeIDFuzzy(t *testing.T) {
+	m := newTestMappings()
+
+	rid, ok := m.StateToRuntimeIDFuzzy("minecraft:stone", map[string]any{"stone_type": "granite"})
+	if !ok {
+		t.Fatal("expected a match for stone_type=granite")
+	}
+	if rid != 1 {
+		t.Fatalf("StateToRuntimeIDFuzzy() = %d, want the lowest runtime ID whose remainder matches defaults (1)", rid)
+	}
+
+	if _, ok := m.StateToRuntimeIDFuzzy("minecraft:stone", map[string]any{"stone_type": "diorite"}); ok {
+		t.Fatal("expected no match for a stone_type that was never registered")
+	}
+	if _, ok := m.StateToRuntimeIDFuzzy("minecraft:unknown", nil); ok {
+		t.Fatal("expected no match for a block with no registered defaults")
+	}
+}
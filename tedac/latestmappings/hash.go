@@ -0,0 +1,99 @@
+package latestmappings
+
+import (
+	"fmt"
+	"hash/fnv"
+	"io"
+	"sort"
+)
+
+// airHash is the FNV-1a offset basis, reserved by newer versions of the Bedrock protocol to always identify the
+// default air block state, regardless of its (trivial) NBT encoding.
+const airHash uint32 = 0x811C9DC5
+
+// nbtHash computes the FNV-1a hash of the canonical little-endian NBT encoding of a block state's name and
+// properties. minecraft:air is special-cased to airHash, matching the reserved value newer clients/servers use.
+func nbtHash(name string, properties map[string]any) uint32 {
+	if name == "minecraft:air" {
+		return airHash
+	}
+	h := fnv.New32a()
+	writeCanonicalState(h, name, properties)
+	return h.Sum32()
+}
+
+// NBT tag type IDs, as defined by the Bedrock NBT format.
+const (
+	tagEnd      = 0
+	tagByte     = 1
+	tagInt      = 3
+	tagString   = 8
+	tagCompound = 10
+)
+
+// writeCanonicalState writes the canonical little-endian NBT encoding of a block state's name and properties to
+// w. Properties are written in sorted key order so that the same state always produces the same bytes,
+// regardless of map iteration order.
+func writeCanonicalState(w io.Writer, name string, properties map[string]any) {
+	writeTag(w, tagString, "name")
+	writeString(w, name)
+
+	writeTag(w, tagCompound, "states")
+	writeProperties(w, properties)
+
+	w.Write([]byte{tagEnd})
+}
+
+// writeProperties writes the canonical little-endian NBT encoding of a compound's properties to w, using the
+// same key order and type handling as hashProperties.
+func writeProperties(w io.Writer, properties map[string]any) {
+	keys := make([]string, 0, len(properties))
+	for k := range properties {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		switch v := properties[k].(type) {
+		case bool:
+			writeTag(w, tagByte, k)
+			if v {
+				w.Write([]byte{1})
+			} else {
+				w.Write([]byte{0})
+			}
+		case uint8:
+			writeTag(w, tagByte, k)
+			w.Write([]byte{v})
+		case int32:
+			writeTag(w, tagInt, k)
+			writeInt32(w, v)
+		case string:
+			writeTag(w, tagString, k)
+			writeString(w, v)
+		default:
+			// If block encoding is broken, we want to find out as soon as possible. This saves a lot of time
+			// debugging in-game.
+			panic(fmt.Sprintf("invalid block property type %T for property %v", v, k))
+		}
+	}
+	w.Write([]byte{tagEnd})
+}
+
+// writeTag writes an NBT tag header: the tag type ID followed by its little-endian length-prefixed name.
+func writeTag(w io.Writer, tagType byte, name string) {
+	w.Write([]byte{tagType})
+	writeString(w, name)
+}
+
+// writeString writes a string in the little-endian length-prefixed form NBT uses for tag names and TAG_String
+// payloads.
+func writeString(w io.Writer, s string) {
+	w.Write([]byte{byte(len(s)), byte(len(s) >> 8)})
+	io.WriteString(w, s)
+}
+
+// writeInt32 writes v as a little-endian TAG_Int payload.
+func writeInt32(w io.Writer, v int32) {
+	w.Write([]byte{byte(v), byte(v >> 8), byte(v >> 16), byte(v >> 24)})
+}
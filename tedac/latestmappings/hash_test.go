@@ -0,0 +1,59 @@
+package latestmappings
+
+import (
+	"bytes"
+	"hash/fnv"
+	"testing"
+)
+
+// TestNbtHashCanonicalEncoding pins nbtHash's canonical NBT encoding to a byte sequence built independently of
+// writeCanonicalState/writeProperties, so a change to the wire format (tag order, length-prefix width, etc.)
+// is caught even though the round trip through StateToHash/HashToState stays internally self-consistent.
+//
+// This only locks our own encoding in place. It is not cross-checked against a hash value observed from a real
+// Bedrock client/server, since this sandbox has no network access to obtain one; that cross-check is still
+// owed before the non-air case is trusted for wire compatibility.
+func TestNbtHashCanonicalEncoding(t *testing.T) {
+	const name = "minecraft:bedrock"
+
+	var want bytes.Buffer
+	want.WriteByte(tagString)
+	writeLengthPrefixed(&want, "name")
+	writeLengthPrefixed(&want, name)
+	want.WriteByte(tagCompound)
+	writeLengthPrefixed(&want, "states")
+	want.WriteByte(tagEnd)
+	want.WriteByte(tagEnd)
+
+	wantHash := fnv.New32a()
+	wantHash.Write(want.Bytes())
+
+	if got := nbtHash(name, nil); got != wantHash.Sum32() {
+		t.Fatalf("nbtHash(%q, nil) = %#x, want %#x (encoded bytes %x)", name, got, wantHash.Sum32(), want.Bytes())
+	}
+}
+
+// writeLengthPrefixed writes s the way NBT tag names and TAG_String payloads are encoded: a little-endian
+// 16-bit length followed by the raw bytes.
+func writeLengthPrefixed(w *bytes.Buffer, s string) {
+	w.WriteByte(byte(len(s)))
+	w.WriteByte(byte(len(s) >> 8))
+	w.WriteString(s)
+}
+
+func TestNbtHashAirIsReserved(t *testing.T) {
+	if got := nbtHash("minecraft:air", nil); got != airHash {
+		t.Fatalf("nbtHash(minecraft:air) = %#x, want reserved airHash %#x", got, airHash)
+	}
+	if got := nbtHash("minecraft:air", map[string]any{"stale": int32(1)}); got != airHash {
+		t.Fatalf("nbtHash(minecraft:air) with properties = %#x, want reserved airHash %#x (properties must be ignored)", got, airHash)
+	}
+}
+
+func TestNbtHashDeterministicRegardlessOfPropertyOrder(t *testing.T) {
+	a := nbtHash("minecraft:stone", map[string]any{"stone_type": "granite", "variant": int32(1)})
+	b := nbtHash("minecraft:stone", map[string]any{"variant": int32(1), "stone_type": "granite"})
+	if a != b {
+		t.Fatalf("nbtHash must not depend on map iteration order: got %#x and %#x for the same properties", a, b)
+	}
+}
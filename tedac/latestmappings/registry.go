@@ -0,0 +1,23 @@
+package latestmappings
+
+import "sync"
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[int32]*Mappings{}
+)
+
+// Register registers m as the Mappings to use for the given protocol version. A later call to For with the same
+// protocol returns m. Register is safe for concurrent use.
+func Register(protocol int32, m *Mappings) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[protocol] = m
+}
+
+// For returns the Mappings registered for protocol, or nil if no Mappings were registered for it.
+func For(protocol int32) *Mappings {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return registry[protocol]
+}
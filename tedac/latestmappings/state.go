@@ -4,10 +4,14 @@ import (
 	"bytes"
 	_ "embed"
 	"fmt"
-	"github.com/sandertv/gophertunnel/minecraft/nbt"
+	"io"
+	"io/fs"
 	"sort"
 	"strings"
 	"unsafe"
+
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
 )
 
 // State holds a combination of a name and properties, together with a version.
@@ -20,74 +24,216 @@ type State struct {
 	Version int32 `nbt:"version"`
 }
 
-var (
-	//go:embed block_states.nbt
-	blockStateData []byte
-	// stateRuntimeIDs holds a map for looking up the runtime ID of a block by the stateHash it produces.
-	stateRuntimeIDs = map[stateHash]uint32{}
-	// runtimeIDToState holds a map for looking up the blockState of a block by its runtime ID.
-	runtimeIDToState = map[uint32]State{}
-)
+// Mappings holds the block and item tables needed to translate between Bedrock's stable block/item identifiers
+// and the runtime IDs and hashes used to refer to them over the network, for a single protocol version. A
+// Mappings is safe for concurrent read use once built.
+type Mappings struct {
+	stateRuntimeIDs  map[stateHash]uint32
+	runtimeIDToState map[uint32]State
 
-var (
-	//go:embed item_runtime_ids.nbt
-	itemRuntimeIDData []byte
-	// itemRuntimeIDsToNames holds a map to translate item runtime IDs to string IDs.
-	itemRuntimeIDsToNames = map[int32]string{}
-	// itemNamesToRuntimeIDs holds a map to translate item string IDs to runtime IDs.
-	itemNamesToRuntimeIDs = map[string]int32{}
-)
+	stateHashes map[stateHash]uint32
+	hashToState map[uint32]State
+
+	// runtimeIDsByName holds, for each block name, the runtime IDs of all of its registered states in the order
+	// they were declared in the source data. The first entry of each is used as that block's defaults.
+	runtimeIDsByName map[string][]uint32
+	defaults         map[string]map[string]any
+
+	itemRuntimeIDsToNames map[int32]string
+	itemNamesToRuntimeIDs map[string]int32
+}
+
+// NewMappings reads block states and item runtime IDs, both NBT encoded, from blockStates and itemRuntimeIDs
+// respectively, and builds a *Mappings from them. It allows downstream projects to supply their own mapping
+// data for protocol versions tedac does not embed itself.
+func NewMappings(blockStates, itemRuntimeIDs io.Reader) (*Mappings, error) {
+	m := &Mappings{
+		stateRuntimeIDs:       map[stateHash]uint32{},
+		runtimeIDToState:      map[uint32]State{},
+		stateHashes:           map[stateHash]uint32{},
+		hashToState:           map[uint32]State{},
+		runtimeIDsByName:      map[string][]uint32{},
+		defaults:              map[string]map[string]any{},
+		itemRuntimeIDsToNames: map[int32]string{},
+		itemNamesToRuntimeIDs: map[string]int32{},
+	}
 
-// init initializes the item and state mappings.
-func init() {
 	var items map[string]int32
-	if err := nbt.Unmarshal(itemRuntimeIDData, &items); err != nil {
-		panic(err)
+	if err := nbt.NewDecoder(itemRuntimeIDs).Decode(&items); err != nil {
+		return nil, fmt.Errorf("latestmappings: decode item runtime IDs: %w", err)
 	}
 	for name, rid := range items {
-		itemNamesToRuntimeIDs[name] = rid
-		itemRuntimeIDsToNames[rid] = name
+		m.itemNamesToRuntimeIDs[name] = rid
+		m.itemRuntimeIDsToNames[rid] = name
 	}
 
-	dec := nbt.NewDecoder(bytes.NewBuffer(blockStateData))
+	dec := nbt.NewDecoder(blockStates)
 
-	// Register all block states present in the block_states.nbt file. These are all possible options registered
-	// blocks may encode to.
+	// Register all block states present in the data. These are all possible options registered blocks may
+	// encode to.
 	var s State
 	for {
 		if err := dec.Decode(&s); err != nil {
 			break
 		}
-		rid := uint32(len(stateRuntimeIDs))
-		stateRuntimeIDs[stateHash{name: s.Name, properties: hashProperties(s.Properties)}] = rid
-		runtimeIDToState[rid] = s
+		rid := uint32(len(m.stateRuntimeIDs))
+		key := stateHash{name: s.Name, properties: hashProperties(s.Properties)}
+		hash := nbtHash(s.Name, s.Properties)
+
+		m.stateRuntimeIDs[key] = rid
+		m.runtimeIDToState[rid] = s
+		m.stateHashes[key] = hash
+		m.hashToState[hash] = s
+
+		if _, ok := m.defaults[s.Name]; !ok {
+			// The first state declared for a block name is its canonical default, matching the order block
+			// states are declared in the source data.
+			m.defaults[s.Name] = s.Properties
+		}
+		m.runtimeIDsByName[s.Name] = append(m.runtimeIDsByName[s.Name], rid)
 	}
+	return m, nil
+}
+
+// NewMappingsFS is like NewMappings, but reads the block states and item runtime IDs from the files at
+// blockStatesPath and itemRuntimeIDsPath within fsys, rather than from two pre-opened readers.
+func NewMappingsFS(fsys fs.FS, blockStatesPath, itemRuntimeIDsPath string) (*Mappings, error) {
+	blockStates, err := fsys.Open(blockStatesPath)
+	if err != nil {
+		return nil, fmt.Errorf("latestmappings: open block states: %w", err)
+	}
+	defer blockStates.Close()
+
+	itemRuntimeIDs, err := fsys.Open(itemRuntimeIDsPath)
+	if err != nil {
+		return nil, fmt.Errorf("latestmappings: open item runtime IDs: %w", err)
+	}
+	defer itemRuntimeIDs.Close()
+
+	return NewMappings(blockStates, itemRuntimeIDs)
 }
 
 // StateToRuntimeID converts a name and its state properties to a runtime ID.
-func StateToRuntimeID(name string, properties map[string]any) (runtimeID uint32, found bool) {
-	rid, ok := stateRuntimeIDs[stateHash{name: name, properties: hashProperties(properties)}]
+func (m *Mappings) StateToRuntimeID(name string, properties map[string]any) (runtimeID uint32, found bool) {
+	rid, ok := m.stateRuntimeIDs[stateHash{name: name, properties: hashProperties(properties)}]
 	return rid, ok
 }
 
-// RuntimeIDToState converts a runtime ID to a name and its state properties.
-func RuntimeIDToState(runtimeID uint32) (name string, properties map[string]any, found bool) {
-	s := runtimeIDToState[runtimeID]
-	return s.Name, s.Properties, true
+// RuntimeIDToState converts a runtime ID to a name and its state properties. The returned properties are a
+// copy, so callers are free to mutate them without corrupting the Mappings' internal state.
+func (m *Mappings) RuntimeIDToState(runtimeID uint32) (name string, properties map[string]any, found bool) {
+	s := m.runtimeIDToState[runtimeID]
+	return s.Name, cloneProperties(s.Properties), true
+}
+
+// StateToHash converts a name and its state properties to the FNV-1a hash that newer versions of the Bedrock
+// protocol use to identify block states, rather than a session-scoped runtime ID.
+func (m *Mappings) StateToHash(name string, properties map[string]any) (hash uint32, found bool) {
+	hash, found = m.stateHashes[stateHash{name: name, properties: hashProperties(properties)}]
+	return hash, found
+}
+
+// HashToState converts an FNV-1a block state hash, as produced by StateToHash, back into the name and
+// properties of the block state it was computed from. The returned properties are a copy, so callers are free
+// to mutate them without corrupting the Mappings' internal state.
+func (m *Mappings) HashToState(hash uint32) (name string, properties map[string]any, found bool) {
+	s, ok := m.hashToState[hash]
+	return s.Name, cloneProperties(s.Properties), ok
 }
 
 // ItemRuntimeIDToName converts an item runtime ID to a string ID.
-func ItemRuntimeIDToName(runtimeID int32) (name string, found bool) {
-	name, ok := itemRuntimeIDsToNames[runtimeID]
+func (m *Mappings) ItemRuntimeIDToName(runtimeID int32) (name string, found bool) {
+	name, ok := m.itemRuntimeIDsToNames[runtimeID]
 	return name, ok
 }
 
 // ItemNameToRuntimeID converts a string ID to an item runtime ID.
-func ItemNameToRuntimeID(name string) (runtimeID int32, found bool) {
-	rid, ok := itemNamesToRuntimeIDs[name]
+func (m *Mappings) ItemNameToRuntimeID(name string) (runtimeID int32, found bool) {
+	rid, ok := m.itemNamesToRuntimeIDs[name]
 	return rid, ok
 }
 
+var (
+	//go:embed block_states.nbt
+	blockStateData []byte
+	//go:embed item_runtime_ids.nbt
+	itemRuntimeIDData []byte
+)
+
+// latest is the Mappings built from the block states and item runtime IDs embedded in this package. It is
+// registered under protocol.CurrentProtocol and backs the package-level functions below.
+var latest *Mappings
+
+// init builds latest from the embedded block state and item runtime ID data and registers it for
+// protocol.CurrentProtocol.
+func init() {
+	m, err := NewMappings(bytes.NewReader(blockStateData), bytes.NewReader(itemRuntimeIDData))
+	if err != nil {
+		panic(err)
+	}
+	latest = m
+	Register(protocol.CurrentProtocol, latest)
+}
+
+// StateToRuntimeID converts a name and its state properties to a runtime ID, using the mappings embedded in
+// this package. For other protocol versions, use For(protocol).StateToRuntimeID.
+func StateToRuntimeID(name string, properties map[string]any) (runtimeID uint32, found bool) {
+	return latest.StateToRuntimeID(name, properties)
+}
+
+// RuntimeIDToState converts a runtime ID to a name and its state properties, using the mappings embedded in
+// this package. For other protocol versions, use For(protocol).RuntimeIDToState.
+func RuntimeIDToState(runtimeID uint32) (name string, properties map[string]any, found bool) {
+	return latest.RuntimeIDToState(runtimeID)
+}
+
+// StateToHash converts a name and its state properties to an FNV-1a block state hash, using the mappings
+// embedded in this package. For other protocol versions, use For(protocol).StateToHash.
+func StateToHash(name string, properties map[string]any) (hash uint32, found bool) {
+	return latest.StateToHash(name, properties)
+}
+
+// HashToState converts an FNV-1a block state hash to a name and its state properties, using the mappings
+// embedded in this package. For other protocol versions, use For(protocol).HashToState.
+func HashToState(hash uint32) (name string, properties map[string]any, found bool) {
+	return latest.HashToState(hash)
+}
+
+// ItemRuntimeIDToName converts an item runtime ID to a string ID, using the mappings embedded in this package.
+// For other protocol versions, use For(protocol).ItemRuntimeIDToName.
+func ItemRuntimeIDToName(runtimeID int32) (name string, found bool) {
+	return latest.ItemRuntimeIDToName(runtimeID)
+}
+
+// ItemNameToRuntimeID converts a string ID to an item runtime ID, using the mappings embedded in this package.
+// For other protocol versions, use For(protocol).ItemNameToRuntimeID.
+func ItemNameToRuntimeID(name string) (runtimeID int32, found bool) {
+	return latest.ItemNameToRuntimeID(name)
+}
+
+// StateToRuntimeIDFuzzy converts a name and a partial set of its state properties to a runtime ID, using the
+// mappings embedded in this package. For other protocol versions, use For(protocol).StateToRuntimeIDFuzzy.
+func StateToRuntimeIDFuzzy(name string, partial map[string]any) (runtimeID uint32, found bool) {
+	return latest.StateToRuntimeIDFuzzy(name, partial)
+}
+
+// Defaults returns the default state properties of the block with the given name, using the mappings embedded
+// in this package. For other protocol versions, use For(protocol).Defaults.
+func Defaults(name string) (properties map[string]any, found bool) {
+	return latest.Defaults(name)
+}
+
+// cloneProperties returns a copy of properties that is safe for callers to mutate without affecting the
+// Mappings it was obtained from. A non-nil, possibly empty, map is always returned, even for blocks that have
+// no properties.
+func cloneProperties(properties map[string]any) map[string]any {
+	clone := make(map[string]any, len(properties))
+	for k, v := range properties {
+		clone[k] = v
+	}
+	return clone
+}
+
 // stateHash is a struct that may be used as a map key for block states. It contains the name of the block state
 // and an encoded version of the properties.
 type stateHash struct {
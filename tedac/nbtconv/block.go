@@ -0,0 +1,44 @@
+package nbtconv
+
+import "github.com/Flonja/tedac/latestmappings"
+
+// BlockEntity is the decoded form of a block actor's NBT: its position plus its full NBT data, which varies per
+// block entity type.
+type BlockEntity struct {
+	// X, Y, Z hold the position of the block entity.
+	X, Y, Z int32
+	// NBTData holds the block entity's whole NBT compound as passed to BlockEntityFromNBT, position keys included.
+	// BlockEntityToNBT writes the position into this same map.
+	NBTData map[string]any
+}
+
+// BlockEntityFromNBT converts the NBT compound m of a block actor into a BlockEntity.
+func BlockEntityFromNBT(m map[string]any) BlockEntity {
+	return BlockEntity{X: Int32(m, "x"), Y: Int32(m, "y"), Z: Int32(m, "z"), NBTData: m}
+}
+
+// BlockEntityToNBT converts a BlockEntity back into the NBT compound Bedrock uses for a block actor.
+func BlockEntityToNBT(be BlockEntity) map[string]any {
+	m := be.NBTData
+	if m == nil {
+		m = map[string]any{}
+	}
+	WriteInt32(m, "x", be.X)
+	WriteInt32(m, "y", be.Y)
+	WriteInt32(m, "z", be.Z)
+	return m
+}
+
+// NestedStateRuntimeID resolves the runtime ID of a block state nested inside a block actor's NBT under key,
+// such as the planted block of a flower pot, which some versions encode as its own {name, states} compound
+// rather than a separate runtime ID. It returns found as false if key is absent or the state is not known to
+// latestmappings.
+func NestedStateRuntimeID(m map[string]any, key string) (runtimeID uint32, found bool) {
+	nested := Map(m, key)
+	if nested == nil {
+		return 0, false
+	}
+	name, _ := nested["name"].(string)
+	properties, _ := nested["states"].(map[string]any)
+	return latestmappings.StateToRuntimeID(name, properties)
+}
@@ -0,0 +1,49 @@
+package nbtconv
+
+import "github.com/Flonja/tedac/latestmappings"
+
+// ItemStack is the decoded form of the NBT compound Bedrock uses to represent an item stack, with its runtime
+// ID resolved to a name using latestmappings.
+type ItemStack struct {
+	// Name is the string ID of the item, resolved from its runtime ID.
+	Name string
+	// Meta is the item's metadata/damage value.
+	Meta int16
+	// Count is the number of items in the stack.
+	Count byte
+	// NBTData is the item's own NBT tag, if it has one, left untouched.
+	NBTData map[string]any
+}
+
+// ItemStackFromNBT converts the NBT compound m, as found in inventory contents and block entities, into an
+// ItemStack. It returns found as false if the item's runtime ID could not be resolved to a name.
+func ItemStackFromNBT(m map[string]any) (stack ItemStack, found bool) {
+	name, ok := latestmappings.ItemRuntimeIDToName(int32(Int16(m, "id")))
+	if !ok {
+		return ItemStack{}, false
+	}
+	return ItemStack{
+		Name:    name,
+		Meta:    Int16(m, "Damage"),
+		Count:   Byte(m, "Count"),
+		NBTData: Map(m, "tag"),
+	}, true
+}
+
+// ItemStackToNBT converts an ItemStack into the NBT compound Bedrock uses to represent an item stack in
+// inventory contents and block entities. It returns found as false if the item's name could not be resolved to
+// a runtime ID.
+func ItemStackToNBT(stack ItemStack) (m map[string]any, found bool) {
+	rid, ok := latestmappings.ItemNameToRuntimeID(stack.Name)
+	if !ok {
+		return nil, false
+	}
+	m = map[string]any{}
+	WriteInt16(m, "id", int16(rid))
+	WriteInt16(m, "Damage", stack.Meta)
+	WriteByte(m, "Count", stack.Count)
+	if stack.NBTData != nil {
+		WriteMap(m, "tag", stack.NBTData)
+	}
+	return m, true
+}
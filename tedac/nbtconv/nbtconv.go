@@ -0,0 +1,122 @@
+// Package nbtconv provides typed accessors for the map[string]any representation NBT compounds take once
+// decoded, plus helpers for translating the item stack and block entity NBT that flows through tedac's packet
+// translators, built on top of the runtime ID tables latestmappings exposes.
+package nbtconv
+
+// Byte reads a byte value from m under key. It returns the zero value if the key is absent or not a byte.
+func Byte(m map[string]any, key string) byte {
+	v, _ := m[key].(byte)
+	return v
+}
+
+// Bool reads a bool value from m under key, where Bedrock encodes booleans as a byte. It returns false if the
+// key is absent or not a byte.
+func Bool(m map[string]any, key string) bool {
+	return Byte(m, key) != 0
+}
+
+// Int16 reads an int16 value from m under key. It returns the zero value if the key is absent or not an int16.
+func Int16(m map[string]any, key string) int16 {
+	v, _ := m[key].(int16)
+	return v
+}
+
+// Int32 reads an int32 value from m under key. It returns the zero value if the key is absent or not an int32.
+func Int32(m map[string]any, key string) int32 {
+	v, _ := m[key].(int32)
+	return v
+}
+
+// Int64 reads an int64 value from m under key. It returns the zero value if the key is absent or not an int64.
+func Int64(m map[string]any, key string) int64 {
+	v, _ := m[key].(int64)
+	return v
+}
+
+// Float32 reads a float32 value from m under key. It returns the zero value if the key is absent or not a
+// float32.
+func Float32(m map[string]any, key string) float32 {
+	v, _ := m[key].(float32)
+	return v
+}
+
+// String reads a string value from m under key. It returns the zero value if the key is absent or not a string.
+func String(m map[string]any, key string) string {
+	v, _ := m[key].(string)
+	return v
+}
+
+// Map reads a nested compound from m under key. It returns nil if the key is absent or not a compound.
+func Map(m map[string]any, key string) map[string]any {
+	v, _ := m[key].(map[string]any)
+	return v
+}
+
+// Slice reads a list of T from m under key, dropping any element that is not a T. It returns nil if the key is
+// absent or not a list.
+func Slice[T any](m map[string]any, key string) []T {
+	v, ok := m[key].([]any)
+	if !ok {
+		return nil
+	}
+	s := make([]T, 0, len(v))
+	for _, e := range v {
+		if t, ok := e.(T); ok {
+			s = append(s, t)
+		}
+	}
+	return s
+}
+
+// WriteByte writes v to m under key.
+func WriteByte(m map[string]any, key string, v byte) {
+	m[key] = v
+}
+
+// WriteBool writes v to m under key, normalised to the byte Bedrock expects for a boolean.
+func WriteBool(m map[string]any, key string, v bool) {
+	if v {
+		m[key] = byte(1)
+		return
+	}
+	m[key] = byte(0)
+}
+
+// WriteInt16 writes v to m under key.
+func WriteInt16(m map[string]any, key string, v int16) {
+	m[key] = v
+}
+
+// WriteInt32 writes v to m under key.
+func WriteInt32(m map[string]any, key string, v int32) {
+	m[key] = v
+}
+
+// WriteInt64 writes v to m under key.
+func WriteInt64(m map[string]any, key string, v int64) {
+	m[key] = v
+}
+
+// WriteFloat32 writes v to m under key.
+func WriteFloat32(m map[string]any, key string, v float32) {
+	m[key] = v
+}
+
+// WriteString writes v to m under key.
+func WriteString(m map[string]any, key string, v string) {
+	m[key] = v
+}
+
+// WriteMap writes v to m under key.
+func WriteMap(m map[string]any, key string, v map[string]any) {
+	m[key] = v
+}
+
+// WriteSlice writes v to m under key, normalised to the []any form NBT lists take once decoded.
+func WriteSlice[T any](m map[string]any, key string, v []T) {
+	s := make([]any, len(v))
+	for i, e := range v {
+		s[i] = e
+	}
+	m[key] = s
+}